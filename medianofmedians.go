@@ -0,0 +1,122 @@
+package partial
+
+import (
+	"golang.org/x/exp/constraints"
+	"math"
+)
+
+// introselectLimit bounds how many partitioning iterations floydRivest may
+// spend on a subrange of size n before it gives up on the sampled pivot and
+// falls back to medianOfMediansSelect, the same introselect strategy Go's
+// pdqsort uses to cap pathological inputs with a heapsort fallback.
+func introselectLimit(n int) int {
+	if n < 2 {
+		return 1
+	}
+	return 2 * int(math.Log2(float64(n)))
+}
+
+// orderedCmp adapts orderedLess, the NaN-aware < for constraints.Ordered, into
+// a three-way cmp function for use with medianOfMediansSelect.
+func orderedCmp[E constraints.Ordered](a, b E) int {
+	switch {
+	case orderedLess(a, b):
+		return -1
+	case orderedLess(b, a):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// cmpFromLess adapts a less function into a three-way cmp function, for
+// passing a less-based comparator to medianOfMediansSelect.
+func cmpFromLess[E any](less func(E, E) bool) func(E, E) int {
+	return func(a, b E) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	}
+}
+
+// medianOfMediansSelect partitions x[left:right+1] around the kth smallest
+// element using the median-of-medians (BFPRT) algorithm, which is worst-case
+// O(n) regardless of input order. It is slower in the common case than
+// floydRivest's sampled pivot, so it's only used as a fallback once
+// introselectLimit has been exceeded.
+// https://en.wikipedia.org/wiki/Median_of_medians
+func medianOfMediansSelect[E any](x []E, left, right, k int, cmp func(E, E) int) {
+	for {
+		if right-left < 5 {
+			insertionSortRange(x, left, right, cmp)
+			return
+		}
+
+		// Partition x[left:right+1] into groups of 5, sort each group in
+		// place, and move each group's median to the front of the range.
+		numGroups := 0
+		for i := left; i <= right; i += 5 {
+			groupRight := min(i+4, right)
+			insertionSortRange(x, i, groupRight, cmp)
+			medianIdx := i + (groupRight-i)/2
+			x[left+numGroups], x[medianIdx] = x[medianIdx], x[left+numGroups]
+			numGroups++
+		}
+
+		// The median of medians is the element that would sit in the middle
+		// of x[left : left+numGroups]; find it with the same algorithm.
+		mid := left + numGroups/2
+		medianOfMediansSelect(x, left, left+numGroups-1, mid, cmp)
+
+		// Partition the whole range around that pivot (Lomuto scheme).
+		pivot := x[mid]
+		x[mid], x[right] = x[right], x[mid]
+		store := left
+		for i := left; i < right; i++ {
+			if cmp(x[i], pivot) < 0 {
+				x[i], x[store] = x[store], x[i]
+				store++
+			}
+		}
+		x[store], x[right] = x[right], x[store]
+
+		// Group the elements equal to the pivot right after it, so that runs
+		// of duplicate keys are settled in one pass instead of one per round.
+		eq := store
+		for i := store + 1; i <= right; i++ {
+			if cmp(x[i], pivot) == 0 {
+				eq++
+				x[i], x[eq] = x[eq], x[i]
+			}
+		}
+
+		switch {
+		case k < store:
+			right = store - 1
+		case k > eq:
+			left = eq + 1
+		default:
+			return
+		}
+	}
+}
+
+// insertionSortRange sorts x[left:right+1] in place. It's only ever called on
+// the small (at most 5-element) groups medianOfMediansSelect builds, where
+// insertion sort beats any asymptotically faster algorithm.
+func insertionSortRange[E any](x []E, left, right int, cmp func(E, E) int) {
+	for i := left + 1; i <= right; i++ {
+		v := x[i]
+		j := i - 1
+		for j >= left && cmp(x[j], v) > 0 {
+			x[j+1] = x[j]
+			j--
+		}
+		x[j+1] = v
+	}
+}
@@ -0,0 +1,70 @@
+package partial
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestMedianOfMediansSelect(t *testing.T) {
+	cmp := func(a, b int) int { return a - b }
+	rand.Seed(7)
+	for trial := 0; trial < 200; trial++ {
+		n := 1 + rand.Intn(50)
+		x := make([]int, n)
+		for i := range x {
+			x[i] = rand.Intn(10)
+		}
+		k := rand.Intn(n)
+
+		medianOfMediansSelect(x, 0, n-1, k, cmp)
+
+		if !checkTopKInvariants(x, k+1, cmp) {
+			t.Errorf("invariants failed, k=%v, out=%v", k, x)
+		}
+	}
+}
+
+// adversarialInputs builds inputs known to defeat naive pivot selection
+// strategies: all-equal keys, a sawtooth pattern, and reverse-sorted order.
+func adversarialInputs(n int) map[string][]int {
+	allEqual := make([]int, n)
+	sawtooth := make([]int, n)
+	reverse := make([]int, n)
+	for i := range allEqual {
+		allEqual[i] = 1
+		sawtooth[i] = i % 7
+		reverse[i] = n - i
+	}
+	return map[string][]int{
+		"allEqual": allEqual,
+		"sawtooth": sawtooth,
+		"reverse":  reverse,
+	}
+}
+
+func TestTopKAdversarial(t *testing.T) {
+	cmp := func(a, b int) int { return a - b }
+	for name, x := range adversarialInputs(5_000) {
+		k := len(x) / 2
+		TopK(x, k)
+		if !checkTopKInvariants(x, k, cmp) {
+			t.Errorf("%s: invariants failed for k=%v", name, k)
+		}
+	}
+}
+
+func BenchmarkTopKAdversarial(b *testing.B) {
+	k := 2_500
+	for name, x := range adversarialInputs(5_000) {
+		b.Run(fmt.Sprintf("partial.TopK_%s", name), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				y := make([]int, len(x))
+				copy(y, x)
+				b.StartTimer()
+				TopK(y, k)
+			}
+		})
+	}
+}
@@ -19,10 +19,26 @@ func Sort[E constraints.Ordered](x []E, k int) {
 // SortFunc partially sorts the slice x in ascending order as determined by the
 // less function. Only elements in x[:k] will be in sorted order. This is faster
 // than using slices.SortFunc when k is small relative to the number of elements.
+//
+// Deprecated: use SortFuncCmp, which takes a three-way cmp function matching
+// slices.SortFunc and halves comparator calls when checking for equality.
 func SortFunc[E any](x []E, k int, less func(E, E) bool) {
 	k = min(k, len(x))
 	if k > 0 {
 		floydRivestFunc(x, 0, len(x)-1, k-1, less)
-		slices.SortFunc(x[:k-1], less)
+		slices.SortFunc(x[:k-1], cmpFromLess(less))
+	}
+}
+
+// SortFuncCmp partially sorts the slice x in ascending order as determined by
+// the cmp function. cmp(a, b) should return a negative number when a < b, a
+// positive number when a > b, and zero when a == b, matching slices.SortFunc.
+// Only elements in x[:k] will be in sorted order. This is faster than using
+// slices.SortFunc when k is small relative to the number of elements.
+func SortFuncCmp[E any](x []E, k int, cmp func(E, E) int) {
+	k = min(k, len(x))
+	if k > 0 {
+		floydRivestCmp(x, 0, len(x)-1, k-1, cmp)
+		slices.SortFunc(x[:k-1], cmp)
 	}
 }
@@ -40,9 +40,26 @@ func TestSortFunc(t *testing.T) {
 		{[]person{{"bob", 45}, {"jane", 31}, {"karl", 39}}, 3},
 	}
 	less := func(x, y person) bool { return x.age < y.age }
+	cmp := func(x, y person) int { return x.age - y.age }
 	for _, c := range cases {
 		SortFunc(c.x, c.k, less)
-		if !slices.IsSortedFunc(c.x[:c.k], less) {
+		if !slices.IsSortedFunc(c.x[:c.k], cmp) {
+			t.Errorf("Not sorted, out=%v, k=%v", c.x, c.k)
+		}
+	}
+}
+
+func TestSortFuncCmp(t *testing.T) {
+	cases := []testCase[person]{
+		{[]person{{"bob", 45}, {"jane", 31}}, 1},
+		{[]person{{"bob", 45}, {"jane", 31}}, 2},
+		{[]person{{"bob", 45}, {"jane", 31}, {"karl", 39}}, 2},
+		{[]person{{"bob", 45}, {"jane", 31}, {"karl", 39}}, 3},
+	}
+	cmp := func(x, y person) int { return x.age - y.age }
+	for _, c := range cases {
+		SortFuncCmp(c.x, c.k, cmp)
+		if !slices.IsSortedFunc(c.x[:c.k], cmp) {
 			t.Errorf("Not sorted, out=%v, k=%v", c.x, c.k)
 		}
 	}
@@ -93,7 +110,7 @@ func BenchmarkSort(b *testing.B) {
 				b.StopTimer()
 				y := slices.Clone(x)
 				b.StartTimer()
-				slices.SortFunc(y, func(i, j int) bool { return i < j })
+				slices.SortFunc(y, func(i, j int) int { return i - j })
 			}
 		})
 		b.Run(fmt.Sprintf("partial.Sort_%d", size), func(b *testing.B) {
@@ -0,0 +1,99 @@
+package partial
+
+import (
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// SortStable partially sorts a slice of any ordered type in ascending order,
+// like Sort, but guarantees that equal elements keep their original relative
+// order. Only elements in x[:k] will be in sorted order.
+//
+// SortStable sorts an index array rather than x directly and then gathers
+// x[:k] through it, so it allocates a scratch slice of n ints plus a scratch
+// slice of k elements of type E. If that allocation cost matters and ties
+// don't, prefer Sort.
+func SortStable[E constraints.Ordered](x []E, k int) {
+	stableSort(x, k, func(a, b E) int {
+		switch {
+		case orderedLess(a, b):
+			return -1
+		case orderedLess(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+}
+
+// SortStableFunc partially sorts the slice x in ascending order as determined
+// by the cmp function, like SortFuncCmp, but guarantees that elements cmp
+// considers equal keep their original relative order. Only elements in x[:k]
+// will be in sorted order.
+//
+// SortStableFunc sorts an index array rather than x directly and then
+// gathers x[:k] through it, so it allocates a scratch slice of n ints plus a
+// scratch slice of k elements of type E. If that allocation cost matters and
+// ties don't, prefer SortFuncCmp.
+func SortStableFunc[E any](x []E, k int, cmp func(E, E) int) {
+	stableSort(x, k, cmp)
+}
+
+// stableSort partially sorts x[:k] by cmp, breaking ties on original index so
+// the result is stable, without requiring cmp itself to be stable.
+func stableSort[E any](x []E, k int, cmp func(E, E) int) {
+	n := len(x)
+	k = min(k, n)
+	if k <= 0 {
+		return
+	}
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	idxCmp := func(a, b int) int {
+		if c := cmp(x[a], x[b]); c != 0 {
+			return c
+		}
+		return a - b
+	}
+	floydRivestCmp(idx, 0, n-1, k-1, idxCmp)
+	slices.SortFunc(idx[:k-1], idxCmp)
+	gather(x, idx[:k])
+}
+
+// gather sets new x[i] = old x[idx[i]] for each i < len(idx), via a scratch
+// slice sized len(idx) rather than len(x), since idx[i] may reference either.
+//
+// Some of the k window positions x[:len(idx)] may not appear in idx (their
+// element isn't among the k selected), so before they're overwritten their
+// original value is relocated into one of the positions idx vacates outside
+// the window, keeping the result a permutation of the input.
+func gather[E any](x []E, idx []int) {
+	k := len(idx)
+	tmp := make([]E, k)
+	for i, j := range idx {
+		tmp[i] = x[j]
+	}
+
+	referenced := make([]bool, k)
+	for _, j := range idx {
+		if j < k {
+			referenced[j] = true
+		}
+	}
+
+	hole := 0
+	for _, j := range idx {
+		if j < k {
+			continue
+		}
+		for referenced[hole] {
+			hole++
+		}
+		x[j] = x[hole]
+		hole++
+	}
+
+	copy(x[:k], tmp)
+}
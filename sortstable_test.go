@@ -0,0 +1,70 @@
+package partial
+
+import (
+	"golang.org/x/exp/slices"
+	"math/rand"
+	"testing"
+)
+
+func TestSortStable(t *testing.T) {
+	type tagged struct {
+		key int
+		seq int
+	}
+	rand.Seed(5)
+	for trial := 0; trial < 50; trial++ {
+		n := 1 + rand.Intn(200)
+		x := make([]tagged, n)
+		for i := range x {
+			x[i] = tagged{key: rand.Intn(5), seq: i}
+		}
+		k := 1 + rand.Intn(n)
+
+		cmp := func(a, b tagged) int { return a.key - b.key }
+		original := slices.Clone(x)
+
+		want := slices.Clone(x)
+		slices.SortStableFunc(want, cmp)
+		want = want[:min(k, n)]
+
+		SortStableFunc(x, k, cmp)
+		got := x[:min(k, n)]
+
+		if !slices.Equal(got, want) {
+			t.Errorf("not stable, k=%v, got=%v, want=%v", k, got, want)
+		}
+
+		// seq is unique per element, so breaking ties on it gives a total
+		// order and makes the sort result deterministic regardless of
+		// arrangement, which is what a multiset comparison needs here.
+		fullCmp := func(a, b tagged) int {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+			return a.seq - b.seq
+		}
+		sortedOriginal := slices.Clone(original)
+		slices.SortFunc(sortedOriginal, fullCmp)
+		sortedX := slices.Clone(x)
+		slices.SortFunc(sortedX, fullCmp)
+		if !slices.Equal(sortedX, sortedOriginal) {
+			t.Errorf("not a permutation of the input, in=%v, out=%v", original, x)
+		}
+	}
+}
+
+func TestSortStableOrdered(t *testing.T) {
+	rand.Seed(6)
+	for trial := 0; trial < 20; trial++ {
+		n := 1 + rand.Intn(200)
+		x := make([]int, n)
+		for i := range x {
+			x[i] = rand.Intn(10)
+		}
+		k := 1 + rand.Intn(n)
+		SortStable(x, k)
+		if !slices.IsSorted(x[:min(k, n)]) {
+			t.Errorf("not sorted, k=%v, out=%v", k, x)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package partial
+
+// TopKStream is a stateful, bounded top-k accumulator for streams of unknown
+// or unbounded length, such as values read from an iterator or channel.
+// Unlike the package-level TopK/TopKFuncCmp, which require the whole input
+// in memory, TopKStream maintains a max-heap of at most k elements, giving
+// O(n log k) time and O(k) memory regardless of how large the stream is.
+//
+// The zero value is not usable; construct one with NewTopK.
+type TopKStream[E any] struct {
+	cmp func(E, E) int
+	k   int
+	x   []E // max-heap of at most k elements, root is the largest
+}
+
+// NewTopK returns a TopKStream accumulator that keeps the k smallest
+// elements seen by Push, ordered by the cmp function. cmp(a, b) should
+// return a negative number when a < b, a positive number when a > b, and
+// zero when a == b.
+func NewTopK[E any](k int, cmp func(E, E) int) *TopKStream[E] {
+	return &TopKStream[E]{
+		cmp: cmp,
+		k:   max(k, 0),
+		x:   make([]E, 0, max(k, 0)),
+	}
+}
+
+// Push adds v to the accumulator. If fewer than k elements have been seen so
+// far, v is kept unconditionally; otherwise v replaces the current largest
+// kept element if v is smaller.
+func (t *TopKStream[E]) Push(v E) {
+	if len(t.x) < t.k {
+		t.x = append(t.x, v)
+		t.siftUp(len(t.x) - 1)
+		return
+	}
+	if t.k == 0 || t.cmp(v, t.x[0]) >= 0 {
+		return
+	}
+	t.x[0] = v
+	t.siftDown(0)
+}
+
+// Len returns the number of elements currently kept, which is at most k.
+func (t *TopKStream[E]) Len() int {
+	return len(t.x)
+}
+
+// Result consumes the accumulator and returns the kept elements sorted
+// ascending by cmp. The accumulator must not be reused after calling Result.
+func (t *TopKStream[E]) Result() []E {
+	SortFuncCmp(t.x, len(t.x), t.cmp)
+	return t.x
+}
+
+func (t *TopKStream[E]) siftUp(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if t.cmp(t.x[i], t.x[parent]) <= 0 {
+			break
+		}
+		t.x[i], t.x[parent] = t.x[parent], t.x[i]
+		i = parent
+	}
+}
+
+func (t *TopKStream[E]) siftDown(i int) {
+	n := len(t.x)
+	for {
+		largest := i
+		if left := 2*i + 1; left < n && t.cmp(t.x[left], t.x[largest]) > 0 {
+			largest = left
+		}
+		if right := 2*i + 2; right < n && t.cmp(t.x[right], t.x[largest]) > 0 {
+			largest = right
+		}
+		if largest == i {
+			return
+		}
+		t.x[i], t.x[largest] = t.x[largest], t.x[i]
+		i = largest
+	}
+}
+
+// TopKChan returns the k smallest elements received from ch, ordered
+// ascending by cmp. It drains ch until it is closed.
+func TopKChan[E any](ch <-chan E, k int, cmp func(E, E) int) []E {
+	t := NewTopK[E](k, cmp)
+	for v := range ch {
+		t.Push(v)
+	}
+	return t.Result()
+}
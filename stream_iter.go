@@ -0,0 +1,18 @@
+//go:build go1.23
+
+package partial
+
+import "iter"
+
+// TopKIter returns the k smallest elements of seq, ordered ascending by cmp.
+// It is a convenience wrapper around TopKStream for callers using Go 1.23
+// range-over-func iterators, and is the preferred way to take the top k of a
+// sequence that doesn't fit in memory as a slice.
+func TopKIter[E any](seq iter.Seq[E], k int, cmp func(E, E) int) []E {
+	t := NewTopK[E](k, cmp)
+	seq(func(v E) bool {
+		t.Push(v)
+		return true
+	})
+	return t.Result()
+}
@@ -0,0 +1,18 @@
+//go:build go1.23
+
+package partial
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTopKIter(t *testing.T) {
+	cmp := func(x, y int) int { return x - y }
+	x := []int{5, 3, 8, 1, 9, 2, 7}
+	got := TopKIter(slices.Values(x), 3, cmp)
+	want := []int{1, 2, 3}
+	if !slices.Equal(got, want) {
+		t.Errorf("TopKIter: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,83 @@
+package partial
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestTopKStream(t *testing.T) {
+	rand.Seed(4)
+	cmp := func(x, y int) int { return x - y }
+	for _, size := range []int{0, 1, 5, 100, 10_000} {
+		for _, k := range []int{0, 1, 3, size / 2, size, size + 10} {
+			x := make([]int, size)
+			for i := range x {
+				x[i] = rand.Intn(size + 1)
+			}
+
+			want := slices.Clone(x)
+			TopK(want, k)
+			wantK := min(k, len(want))
+			slices.Sort(want[:wantK])
+			want = want[:wantK]
+
+			s := NewTopK[int](k, cmp)
+			for _, v := range x {
+				s.Push(v)
+			}
+			got := s.Result()
+
+			if !slices.Equal(got, want) {
+				t.Errorf("size=%d k=%d: got %v, want %v", size, k, got, want)
+			}
+		}
+	}
+}
+
+func TestTopKChan(t *testing.T) {
+	cmp := func(x, y int) int { return x - y }
+	x := []int{5, 3, 8, 1, 9, 2, 7}
+	ch := make(chan int)
+	go func() {
+		for _, v := range x {
+			ch <- v
+		}
+		close(ch)
+	}()
+	got := TopKChan(ch, 3, cmp)
+	want := []int{1, 2, 3}
+	if !slices.Equal(got, want) {
+		t.Errorf("TopKChan: got %v, want %v", got, want)
+	}
+}
+
+func BenchmarkTopKStream(b *testing.B) {
+	cmp := func(x, y int) int { return x - y }
+	sizes := []int{1_000, 100_000, 10_000_000}
+	k := 100
+	for _, size := range sizes {
+		var x []int
+		for i := 0; i < size; i++ {
+			x = append(x, rand.Intn(size))
+		}
+		b.Run(fmt.Sprintf("partial.TopK_%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				y := slices.Clone(x)
+				b.StartTimer()
+				TopK(y, k)
+			}
+		})
+		b.Run(fmt.Sprintf("stream.TopK_%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s := NewTopK[int](k, cmp)
+				for _, v := range x {
+					s.Push(v)
+				}
+				s.Result()
+			}
+		})
+	}
+}
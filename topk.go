@@ -22,6 +22,9 @@ func TopK[E constraints.Ordered](x []E, k int) {
 // Only the kth element x[k-1] is guaranteed to be in sorted order. All elements
 // in x[:k-1] are less than or equal to the kth element, all elements in x[k:]
 // are greater than or equal. This is faster than using slices.SortFunc.
+//
+// Deprecated: use TopKFuncCmp, which takes a three-way cmp function matching
+// slices.SortFunc and halves comparator calls when checking for equality.
 func TopKFunc[E any](x []E, k int, less func(E, E) bool) {
 	k = min(k, len(x))
 	if k > 0 {
@@ -29,13 +32,41 @@ func TopKFunc[E any](x []E, k int, less func(E, E) bool) {
 	}
 }
 
+// TopKFuncCmp reorders a slice such that x[:k] contains the first k elements
+// of the slice when sorted in ascending order as determined by the cmp
+// function. cmp(a, b) should return a negative number when a < b, a positive
+// number when a > b, and zero when a == b, matching slices.SortFunc. Only the
+// kth element x[k-1] is guaranteed to be in sorted order. All elements in
+// x[:k-1] are less than or equal to the kth element, all elements in x[k:]
+// are greater than or equal. This is faster than using slices.SortFunc.
+func TopKFuncCmp[E any](x []E, k int, cmp func(E, E) int) {
+	k = min(k, len(x))
+	if k > 0 {
+		floydRivestCmp(x, 0, len(x)-1, k-1, cmp)
+	}
+}
+
 // https://en.wikipedia.org/wiki/Floyd%E2%80%93Rivest_algorithm
+//
+// floydRivest degrades to quadratic time on adversarial inputs that keep
+// defeating the sampled pivot, so it hands off to medianOfMediansSelect,
+// which is worst-case linear, once it has spent more iterations on this
+// subrange than introselectLimit allows.
 func floydRivest[E constraints.Ordered](x []E, left, right, k int) {
+	floydRivestDepth(x, left, right, k, introselectLimit(right-left+1))
+}
+
+func floydRivestDepth[E constraints.Ordered](x []E, left, right, k, depth int) {
 	// left is the left index for the interval
 	// right is the right index for the interval
 	// k is the desired index value, where x[k] is the (k+1)th smallest element when left = 0
 	length := len(x)
 	for right > left {
+		if depth <= 0 {
+			medianOfMediansSelect(x, left, right, k, orderedCmp[E])
+			return
+		}
+		depth--
 		// Use select recursively to sample a smaller set of size s
 		// the arbitrary constants 600 and 0.5 are used in the original
 		// version to minimize execution time.
@@ -48,28 +79,28 @@ func floydRivest[E constraints.Ordered](x []E, left, right, k int) {
 			var kf = float64(k)
 			var newLeft = max(left, int(math.Floor(kf-i*s/n+sd)))
 			var newRight = min(right, int(math.Floor(kf+(n-i)*s/n+sd)))
-			floydRivest(x, newLeft, newRight, k)
+			floydRivestDepth(x, newLeft, newRight, k, depth)
 		}
 		// partition the elements between left and right around t
 		var t = x[k]
 		var i = left
 		var j = right
 		x[left], x[k] = x[k], x[left]
-		if t < x[right] {
+		if orderedLess(t, x[right]) {
 			x[left], x[right] = x[right], x[left]
 		}
 		for i < j {
 			x[i], x[j] = x[j], x[i]
 			i++
 			j--
-			for i < length && x[i] < t {
+			for i < length && orderedLess(x[i], t) {
 				i++
 			}
-			for j >= 0 && t < x[j] {
+			for j >= 0 && orderedLess(t, x[j]) {
 				j--
 			}
 		}
-		if x[left] == t {
+		if !orderedLess(x[left], t) && !orderedLess(t, x[left]) {
 			x[left], x[j] = x[j], x[left]
 		} else {
 			j++
@@ -87,11 +118,20 @@ func floydRivest[E constraints.Ordered](x []E, left, right, k int) {
 }
 
 func floydRivestFunc[E any](x []E, left, right, k int, less func(E, E) bool) {
+	floydRivestFuncDepth(x, left, right, k, less, introselectLimit(right-left+1))
+}
+
+func floydRivestFuncDepth[E any](x []E, left, right, k int, less func(E, E) bool, depth int) {
 	// left is the left index for the interval
 	// right is the right index for the interval
 	// k is the desired index value, where x[k] is the (k+1)th smallest element when left = 0
 	length := len(x)
 	for right > left {
+		if depth <= 0 {
+			medianOfMediansSelect(x, left, right, k, cmpFromLess(less))
+			return
+		}
+		depth--
 		// Use select recursively to sample a smaller set of size s
 		// the arbitrary constants 600 and 0.5 are used in the original
 		// version to minimize execution time.
@@ -104,7 +144,7 @@ func floydRivestFunc[E any](x []E, left, right, k int, less func(E, E) bool) {
 			var kf = float64(k)
 			var newLeft = max(left, int(math.Floor(kf-i*s/n+sd)))
 			var newRight = min(right, int(math.Floor(kf+(n-i)*s/n+sd)))
-			floydRivestFunc(x, newLeft, newRight, k, less)
+			floydRivestFuncDepth(x, newLeft, newRight, k, less, depth)
 		}
 		// partition the elements between left and right around t
 		var t = x[k]
@@ -142,6 +182,75 @@ func floydRivestFunc[E any](x []E, left, right, k int, less func(E, E) bool) {
 	}
 }
 
+// floydRivestCmp is identical to floydRivestFunc except that it takes a
+// three-way cmp function instead of a less function, which allows the
+// x[left] == t equality check to be done with a single comparator call
+// instead of two.
+func floydRivestCmp[E any](x []E, left, right, k int, cmp func(E, E) int) {
+	floydRivestCmpDepth(x, left, right, k, cmp, introselectLimit(right-left+1))
+}
+
+func floydRivestCmpDepth[E any](x []E, left, right, k int, cmp func(E, E) int, depth int) {
+	// left is the left index for the interval
+	// right is the right index for the interval
+	// k is the desired index value, where x[k] is the (k+1)th smallest element when left = 0
+	length := len(x)
+	for right > left {
+		if depth <= 0 {
+			medianOfMediansSelect(x, left, right, k, cmp)
+			return
+		}
+		depth--
+		// Use select recursively to sample a smaller set of size s
+		// the arbitrary constants 600 and 0.5 are used in the original
+		// version to minimize execution time.
+		if right-left > 600 {
+			var n = float64(right - left + 1)
+			var i = float64(k - left + 1)
+			var z = math.Log(n)
+			var s = 0.5 * math.Exp(2*z/3)
+			var sd = 0.5 * math.Sqrt(z*s*(n-s)/n) * float64(sign(i-n/2))
+			var kf = float64(k)
+			var newLeft = max(left, int(math.Floor(kf-i*s/n+sd)))
+			var newRight = min(right, int(math.Floor(kf+(n-i)*s/n+sd)))
+			floydRivestCmpDepth(x, newLeft, newRight, k, cmp, depth)
+		}
+		// partition the elements between left and right around t
+		var t = x[k]
+		var i = left
+		var j = right
+		x[left], x[k] = x[k], x[left]
+		if cmp(t, x[right]) < 0 {
+			x[left], x[right] = x[right], x[left]
+		}
+		for i < j {
+			x[i], x[j] = x[j], x[i]
+			i++
+			j--
+			for i < length && cmp(x[i], t) < 0 {
+				i++
+			}
+			for j >= 0 && cmp(t, x[j]) < 0 {
+				j--
+			}
+		}
+		if cmp(x[left], t) == 0 {
+			x[left], x[j] = x[j], x[left]
+		} else {
+			j++
+			x[j], x[right] = x[right], x[j]
+		}
+		// Adjust left and right towards the boundaries of the subset
+		// containing the (k − left + 1)th smallest element.
+		if j <= k {
+			left = j + 1
+		}
+		if k <= j {
+			right = j - 1
+		}
+	}
+}
+
 func min[E constraints.Ordered](x, y E) E {
 	if x < y {
 		return x
@@ -156,6 +265,20 @@ func max[E constraints.Ordered](x, y E) E {
 	return y
 }
 
+// isNaN reports whether x is a floating-point NaN. For non-float E, x != x is
+// never true and the check compiles away.
+func isNaN[E constraints.Ordered](x E) bool {
+	return x != x
+}
+
+// orderedLess is < with a total order over floats: NaN compares less than
+// every other value, matching the ordering used by cmp.Compare and the Go
+// 1.21 slices package. Without this, a NaN anywhere in x breaks floydRivest's
+// partitioning, since NaN compares false against everything under plain <.
+func orderedLess[E constraints.Ordered](a, b E) bool {
+	return (isNaN(a) && !isNaN(b)) || a < b
+}
+
 func sign(x float64) int {
 	if x < 0 {
 		return -1
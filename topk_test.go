@@ -2,6 +2,7 @@ package partial
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"testing"
 
@@ -75,6 +76,24 @@ type person struct {
 }
 
 func TestTopKFunc(t *testing.T) {
+	cases := []testCase[person]{
+		{[]person{{"bob", 45}, {"jane", 31}}, 1},
+		{[]person{{"bob", 45}, {"jane", 31}}, 2},
+		{[]person{{"bob", 45}, {"jane", 31}, {"karl", 31}}, 2},
+		{[]person{{"bob", 45}, {"jane", 31}, {"karl", 31}}, 3},
+	}
+	less := func(x, y person) bool { return x.age < y.age }
+	for _, c := range cases {
+		x := slices.Clone(c.x)
+		TopKFunc(x, c.k, less)
+		cmp := func(x, y person) int { return x.age - y.age }
+		if !checkTopKInvariants(x, c.k, cmp) {
+			t.Errorf("Invariants failed, in=%v, k=%v, out=%v.", c.x, c.k, x)
+		}
+	}
+}
+
+func TestTopKFuncCmp(t *testing.T) {
 	cases := []testCase[person]{
 		{[]person{{"bob", 45}, {"jane", 31}}, 1},
 		{[]person{{"bob", 45}, {"jane", 31}}, 2},
@@ -84,13 +103,47 @@ func TestTopKFunc(t *testing.T) {
 	cmp := func(x, y person) int { return x.age - y.age }
 	for _, c := range cases {
 		x := slices.Clone(c.x)
-		TopKFunc(x, c.k, cmp)
+		TopKFuncCmp(x, c.k, cmp)
 		if !checkTopKInvariants(x, c.k, cmp) {
 			t.Errorf("Invariants failed, in=%v, k=%v, out=%v.", c.x, c.k, x)
 		}
 	}
 }
 
+func TestTopKNaN(t *testing.T) {
+	rand.Seed(3)
+	cmp := func(x, y float64) int {
+		switch {
+		case isNaN(x) && isNaN(y):
+			return 0
+		case isNaN(x):
+			return -1
+		case isNaN(y):
+			return 1
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	}
+	for trial := 0; trial < 20; trial++ {
+		x := make([]float64, 200)
+		for i := range x {
+			x[i] = rand.Float64()
+		}
+		for i := 0; i < 10; i++ {
+			x[rand.Intn(len(x))] = math.NaN()
+		}
+		k := 1 + rand.Intn(len(x))
+		TopK(x, k)
+		if !checkTopKInvariants(x, k, cmp) {
+			t.Errorf("NaN-seeded invariants failed, k=%v, out=%v", k, x)
+		}
+	}
+}
+
 func TestTopKOutOfBounds(t *testing.T) {
 	cmp := func(x, y int) int { return x - y }
 
@@ -144,7 +197,7 @@ func BenchmarkTopK(b *testing.B) {
 				b.StopTimer()
 				y := slices.Clone(x)
 				b.StartTimer()
-				SortFunc(y, k, func(i, j int) int { return i - j })
+				SortFunc(y, k, func(i, j int) bool { return i < j })
 			}
 		})
 		b.Run(fmt.Sprintf("partial.TopK_%d", size), func(b *testing.B) {
@@ -160,7 +213,7 @@ func BenchmarkTopK(b *testing.B) {
 				b.StopTimer()
 				y := slices.Clone(x)
 				b.StartTimer()
-				TopKFunc(y, k, func(i, j int) int { return i - j })
+				TopKFunc(y, k, func(i, j int) bool { return i < j })
 			}
 		})
 	}
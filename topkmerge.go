@@ -0,0 +1,117 @@
+package partial
+
+import (
+	"golang.org/x/exp/constraints"
+	"sync"
+)
+
+// TopKMerge returns the k smallest elements across several inputs that are
+// each individually sorted ascending, in ascending order. This is the
+// standard building block for sharded/parallel TopK: split a large slice
+// into chunks, run Sort on each chunk in parallel, then merge the sorted
+// prefixes with TopKMerge. Complexity is O(k log m), where m is the number
+// of inputs, independent of their total length.
+func TopKMerge[E constraints.Ordered](k int, sorted ...[]E) []E {
+	return TopKMergeFunc(k, orderedCmp[E], sorted...)
+}
+
+// TopKMergeFunc is like TopKMerge but the inputs are ordered, and the result
+// is merged, according to the cmp function rather than constraints.Ordered's
+// built-in comparison.
+func TopKMergeFunc[E any](k int, cmp func(E, E) int, sorted ...[]E) []E {
+	type cursor struct {
+		source int
+		pos    int
+	}
+	h := make([]cursor, 0, len(sorted))
+	less := func(a, b cursor) bool { return cmp(sorted[a.source][a.pos], sorted[b.source][b.pos]) < 0 }
+	push := func(c cursor) {
+		h = append(h, c)
+		i := len(h) - 1
+		for i > 0 {
+			parent := (i - 1) / 2
+			if !less(h[i], h[parent]) {
+				break
+			}
+			h[i], h[parent] = h[parent], h[i]
+			i = parent
+		}
+	}
+	pop := func() cursor {
+		top := h[0]
+		last := len(h) - 1
+		h[0] = h[last]
+		h = h[:last]
+		i := 0
+		for {
+			smallest := i
+			if l := 2*i + 1; l < len(h) && less(h[l], h[smallest]) {
+				smallest = l
+			}
+			if r := 2*i + 2; r < len(h) && less(h[r], h[smallest]) {
+				smallest = r
+			}
+			if smallest == i {
+				break
+			}
+			h[i], h[smallest] = h[smallest], h[i]
+			i = smallest
+		}
+		return top
+	}
+
+	for source, x := range sorted {
+		if len(x) > 0 {
+			push(cursor{source: source, pos: 0})
+		}
+	}
+
+	result := make([]E, 0, k)
+	for len(result) < k && len(h) > 0 {
+		c := pop()
+		result = append(result, sorted[c.source][c.pos])
+		if c.pos+1 < len(sorted[c.source]) {
+			push(cursor{source: c.source, pos: c.pos + 1})
+		}
+	}
+	return result
+}
+
+// TopKParallel returns the k smallest elements of x in ascending order,
+// like Sort(x, k) followed by x[:k], but shards x across workers goroutines
+// to sort each shard concurrently before merging the sorted shards with
+// TopKMerge. It gives a drop-in parallel replacement for Sort on large
+// slices without callers having to orchestrate the goroutines themselves.
+func TopKParallel[E constraints.Ordered](x []E, k int, workers int) []E {
+	return TopKParallelFunc(x, k, workers, orderedCmp[E])
+}
+
+// TopKParallelFunc is like TopKParallel but elements are ordered according
+// to the cmp function rather than constraints.Ordered's built-in comparison.
+func TopKParallelFunc[E any](x []E, k int, workers int, cmp func(E, E) int) []E {
+	workers = max(1, min(workers, len(x)))
+	k = min(k, len(x))
+
+	shardSize := (len(x) + workers - 1) / workers
+	shards := make([][]E, 0, workers)
+	for start := 0; start < len(x); start += shardSize {
+		end := min(start+shardSize, len(x))
+		shards = append(shards, x[start:end])
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(shards))
+	for _, shard := range shards {
+		go func(shard []E) {
+			defer wg.Done()
+			SortFuncCmp(shard, k, cmp)
+		}(shard)
+	}
+	wg.Wait()
+
+	prefixes := make([][]E, len(shards))
+	for i, shard := range shards {
+		prefixes[i] = shard[:min(k, len(shard))]
+	}
+	return TopKMergeFunc(k, cmp, prefixes...)
+}
@@ -0,0 +1,86 @@
+package partial
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestTopKMerge(t *testing.T) {
+	rand.Seed(8)
+	for trial := 0; trial < 50; trial++ {
+		numShards := 1 + rand.Intn(5)
+		shards := make([][]int, numShards)
+		var all []int
+		for i := range shards {
+			n := rand.Intn(20)
+			shard := make([]int, n)
+			for j := range shard {
+				shard[j] = rand.Intn(50)
+			}
+			slices.Sort(shard)
+			shards[i] = shard
+			all = append(all, shard...)
+		}
+		slices.Sort(all)
+		k := rand.Intn(len(all) + 1)
+
+		got := TopKMerge(k, shards...)
+		want := all[:k]
+		if !slices.Equal(got, want) {
+			t.Errorf("k=%v: got %v, want %v", k, got, want)
+		}
+	}
+}
+
+func TestTopKParallel(t *testing.T) {
+	rand.Seed(9)
+	for trial := 0; trial < 20; trial++ {
+		n := rand.Intn(500)
+		x := make([]int, n)
+		for i := range x {
+			x[i] = rand.Intn(100)
+		}
+		k := rand.Intn(n + 1)
+
+		want := slices.Clone(x)
+		slices.Sort(want)
+		want = want[:k]
+
+		got := TopKParallel(slices.Clone(x), k, 4)
+		if !slices.Equal(got, want) {
+			t.Errorf("k=%v: got %v, want %v", k, got, want)
+		}
+	}
+}
+
+func BenchmarkTopKParallel(b *testing.B) {
+	sizes := []int{10_000, 1_000_000}
+	k := 100
+	for _, size := range sizes {
+		var x []int
+		for i := 0; i < size; i++ {
+			x = append(x, rand.Intn(size))
+		}
+		b.Run(fmt.Sprintf("partial.Sort_%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				y := slices.Clone(x)
+				b.StartTimer()
+				Sort(y, k)
+			}
+		})
+		for _, workers := range []int{2, 4, 8} {
+			b.Run(fmt.Sprintf("partial.TopKParallel_%d_w%d", size, workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					y := slices.Clone(x)
+					b.StartTimer()
+					TopKParallel(y, k, workers)
+				}
+			})
+		}
+	}
+}